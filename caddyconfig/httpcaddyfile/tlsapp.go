@@ -103,11 +103,47 @@ func (st ServerType) buildTLSApp(
 				ap = catchAllAP
 			}
 
+			// named cert profile (tls { profile ... }) overlays its fields onto
+			// the base automation policy; it only needs to specify what it
+			// wants to change, so anything it leaves unset - notably the
+			// issuer - is inherited from ap's existing configuration rather
+			// than being blanked out. Any per-site overrides below still
+			// apply on top of it.
+			if profileVals, ok := sblock.pile["tls.profile"]; ok {
+				for _, profileVal := range profileVals {
+					profileName := profileVal.Value.(string)
+					tmpl, err := certProfileBase(options, &warnings, profileName)
+					if err != nil {
+						return nil, warnings, err
+					}
+					applyCertProfile(ap, tmpl)
+				}
+			}
+
 			// on-demand tls
 			if _, ok := sblock.pile["tls.on_demand"]; ok {
 				ap.OnDemand = true
 			}
 
+			// OCSP must-staple
+			if _, ok := sblock.pile["tls.must_staple"]; ok {
+				ap.MustStaple = true
+			}
+
+			// OCSP stapling (tls { ocsp_stapling off })
+			if ocspVals, ok := sblock.pile["tls.ocsp_stapling"]; ok {
+				for _, ocspVal := range ocspVals {
+					ap.DisableOCSPStapling = ocspVal.Value.(bool)
+				}
+			}
+
+			// required Certificate Transparency SCTs (tls { ct_logs <url>... })
+			if ctVals, ok := sblock.pile["tls.ct_logs"]; ok {
+				for _, ctVal := range ctVals {
+					ap.RequiredSCTs = append(ap.RequiredSCTs, ctVal.Value.([]string)...)
+				}
+			}
+
 			// certificate issuers
 			if issuerVals, ok := sblock.pile["tls.cert_issuer"]; ok {
 				for _, issuerVal := range issuerVals {
@@ -119,26 +155,42 @@ func (st ServerType) buildTLSApp(
 				}
 			}
 
+			// ordered chain of issuers with automatic fallback (tls { issuers ... })
+			if issuerChainVals, ok := sblock.pile["tls.cert_issuers"]; ok {
+				for _, issuerChainVal := range issuerChainVals {
+					names := issuerChainVal.Value.([]string)
+					chain, err := issuerChainFromNames(names, options, warnings)
+					if err != nil {
+						return nil, warnings, err
+					}
+					issuersRaw := encodeIssuerChain(chain, &warnings)
+					if ap == catchAllAP && ap.IssuersRaw != nil && !issuersRawEqual(ap.IssuersRaw, issuersRaw) {
+						return nil, warnings, fmt.Errorf("automation policy from site block is also default/catch-all policy because of key without hostname, and the two are in conflict: %s != %s", ap.IssuersRaw, issuersRaw)
+					}
+					ap.Issuer = nil
+					ap.IssuersRaw = issuersRaw
+				}
+			}
+
 			// custom bind host
 			for _, cfgVal := range sblock.pile["bind"] {
+				// an ordered fallback chain (tls { issuers ... }) has no single
+				// issuer to attach this to, so leave it alone; acmeIssuerFromIssuer
+				// would otherwise treat ap.Issuer's nil as "no issuer configured
+				// yet" and attach the bind host to a disconnected issuer that is
+				// never part of the chain
+				if ap.IssuersRaw != nil {
+					break
+				}
 				// if an issuer was already configured and it is NOT an ACME
 				// issuer, skip, since we intend to adjust only ACME issuers
-				var acmeIssuer *caddytls.ACMEIssuer
-				if ap.Issuer != nil {
-					// ensure we include any issuer that embeds/wraps an underlying ACME issuer
-					type acmeCapable interface{ GetACMEIssuer() *caddytls.ACMEIssuer }
-					if acmeWrapper, ok := ap.Issuer.(acmeCapable); ok {
-						acmeIssuer = acmeWrapper.GetACMEIssuer()
-					} else {
-						break
-					}
+				acmeIssuer, ok := acmeIssuerFromIssuer(ap.Issuer)
+				if !ok {
+					break
 				}
 
 				// proceed to configure the ACME issuer's bind host, without
 				// overwriting any existing settings
-				if acmeIssuer == nil {
-					acmeIssuer = new(caddytls.ACMEIssuer)
-				}
 				if acmeIssuer.Challenges == nil {
 					acmeIssuer.Challenges = new(caddytls.ChallengesConfig)
 				}
@@ -153,6 +205,53 @@ func (st ServerType) buildTLSApp(
 				ap.Issuer = acmeIssuer // we'll encode it later
 			}
 
+			// per-site DNS challenge provider, configured with its own credentials
+			// via the provider module's own Caddyfile unmarshaler (tls { dns ... }).
+			// By the time it reaches us here, the provider token's inner block has
+			// already been dispatched to the named module's UnmarshalCaddyfile by
+			// the tls directive parser, so pile holds a ready-to-use instance.
+			for _, dnsVal := range sblock.pile["tls.dns"] {
+				// as with bind host, an explicit issuer chain has no single
+				// issuer to attach this to
+				if ap.IssuersRaw != nil {
+					break
+				}
+				// as with bind host, only adjust an issuer that is (or wraps) ACME
+				acmeIssuer, ok := acmeIssuerFromIssuer(ap.Issuer)
+				if !ok {
+					break
+				}
+				if acmeIssuer.Challenges == nil {
+					acmeIssuer.Challenges = new(caddytls.ChallengesConfig)
+				}
+				provider := dnsVal.Value.(certmagic.DNSProvider)
+				providerName := caddy.GetModuleName(provider)
+				acmeIssuer.Challenges.DNS = &caddytls.DNSChallengeConfig{
+					ProviderRaw: caddyconfig.JSONModuleObject(provider, "name", providerName, &warnings),
+				}
+				ap.Issuer = acmeIssuer // we'll encode it later
+			}
+
+			// ACME Renewal Information (RFC 9773); overrides the global acme_ari
+			// default for this site's subjects. This is ACME-specific, so (like
+			// bind host and DNS provider above) it applies to the single ACME
+			// (or ACME-wrapping) issuer the site block ends up with, rather than
+			// living on the policy itself; it is not applied to an issuer chain
+			// or to a non-ACME issuer.
+			for _, ariVal := range sblock.pile["tls.ari"] {
+				// as with bind host and DNS provider, an explicit issuer chain has
+				// no single issuer to attach this to
+				if ap.IssuersRaw != nil {
+					break
+				}
+				acmeIssuer, ok := acmeIssuerFromIssuer(ap.Issuer)
+				if !ok {
+					break
+				}
+				acmeIssuer.RenewalInfo = ariVal.Value.(*caddytls.ACMEARIConfig)
+				ap.Issuer = acmeIssuer // we'll encode it later
+			}
+
 			// first make sure this block is allowed to create an automation policy;
 			// doing so is forbidden if it has a key with no host (i.e. ":443")
 			// and if there is a different server block that also has a key with no
@@ -212,6 +311,8 @@ func (st ServerType) buildTLSApp(
 					apCopy := *ap
 					ap2 = &apCopy
 					ap2.Subjects = internal
+					// internal-only subjects never use the public fallback chain
+					ap2.IssuersRaw = nil
 					ap2.IssuerRaw = caddyconfig.JSONModuleObject(caddytls.InternalIssuer{}, "module", "internal", &warnings)
 				}
 			}
@@ -334,6 +435,26 @@ func (st ServerType) buildTLSApp(
 	return tlsApp, warnings, nil
 }
 
+// acmeIssuerFromIssuer extracts the *caddytls.ACMEIssuer embedded in issuer,
+// for applying a site-specific override (bind host, DNS provider, etc.) to
+// it, even if issuer is a wrapper type like ZeroSSLIssuer. If issuer is nil,
+// a fresh ACMEIssuer is returned so the override has something to attach to.
+// If issuer is some other, non-ACME-capable kind of issuer, ok is false and
+// the caller should leave it alone.
+func acmeIssuerFromIssuer(issuer certmagic.Issuer) (acmeIssuer *caddytls.ACMEIssuer, ok bool) {
+	if issuer == nil {
+		return new(caddytls.ACMEIssuer), true
+	}
+	type acmeCapable interface{ GetACMEIssuer() *caddytls.ACMEIssuer }
+	if acmeWrapper, ok := issuer.(acmeCapable); ok {
+		if acmeIssuer := acmeWrapper.GetACMEIssuer(); acmeIssuer != nil {
+			return acmeIssuer, true
+		}
+		return new(caddytls.ACMEIssuer), true
+	}
+	return nil, false
+}
+
 // newBaseAutomationPolicy returns a new TLS automation policy that gets
 // its values from the global options map. It should be used as the base
 // for any other automation policies. A nil policy (and no error) will be
@@ -350,8 +471,9 @@ func newBaseAutomationPolicy(options map[string]interface{}, warnings []caddycon
 	email, hasEmail := options["email"]
 	localCerts, hasLocalCerts := options["local_certs"]
 	keyType, hasKeyType := options["key_type"]
+	_, hasACMEARI := options["acme_ari"]
 
-	hasGlobalAutomationOpts := hasIssuer || hasACMECA || hasACMECARoot || hasACMEDNS || hasACMEEAB || hasEmail || hasLocalCerts || hasKeyType
+	hasGlobalAutomationOpts := hasIssuer || hasACMECA || hasACMECARoot || hasACMEDNS || hasACMEEAB || hasEmail || hasLocalCerts || hasKeyType || hasACMEARI
 
 	// if there are no global options related to automation policies
 	// set, then we can just return right away
@@ -368,7 +490,7 @@ func newBaseAutomationPolicy(options map[string]interface{}, warnings []caddycon
 	}
 
 	if hasIssuer {
-		if hasACMECA || hasACMEDNS || hasACMEEAB || hasEmail || hasLocalCerts {
+		if hasACMECA || hasACMEDNS || hasACMEEAB || hasACMEARI || hasEmail || hasLocalCerts {
 			return nil, fmt.Errorf("global options are ambiguous: cert_issuer is confusing when combined with acme_*, email, or local_certs options")
 		}
 		ap.Issuer = issuer.(certmagic.Issuer)
@@ -376,38 +498,189 @@ func newBaseAutomationPolicy(options map[string]interface{}, warnings []caddycon
 		// internal issuer enabled trumps any ACME configurations; useful in testing
 		ap.Issuer = new(caddytls.InternalIssuer) // we'll encode it later
 	} else {
-		if acmeCA == nil {
-			acmeCA = ""
+		mgr, err := acmeIssuerFromOptions(options, warnings)
+		if err != nil {
+			return nil, err
 		}
-		if email == nil {
-			email = ""
+		ap.Issuer = disambiguateACMEIssuer(mgr) // we'll encode it later
+	}
+
+	return ap, nil
+}
+
+// certProfileBase returns a copy of the named template from the global
+// "cert_profiles" option, for use as an overlay onto the base automation
+// policy of a site block that declares `tls { profile <name> }`. Returning
+// a copy (rather than the template itself) means each referencing site
+// block can overlay its own per-site settings without mutating the shared
+// template or affecting other site blocks that reference the same profile;
+// this includes re-slicing RequiredSCTs, since a shallow struct copy would
+// otherwise still share the template's backing array, and a site block
+// appending to it (tls { ct_logs ... }) would clobber other site blocks
+// that reference the same profile.
+//
+// If the template declared a fallback chain of issuer names
+// (IssuerNames), it is resolved into IssuersRaw here, since doing so needs
+// the full, final set of global options, which isn't guaranteed to be
+// available yet when cert_profiles itself is parsed.
+func certProfileBase(options map[string]interface{}, warnings *[]caddyconfig.Warning, name string) (*caddytls.AutomationPolicy, error) {
+	profiles, ok := options["cert_profiles"].(map[string]*caddytls.AutomationPolicy)
+	if !ok {
+		return nil, fmt.Errorf("cert profile '%s' was requested but no cert_profiles are defined", name)
+	}
+	tmpl, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("cert profile '%s' is not defined", name)
+	}
+	apCopy := *tmpl
+	if tmpl.RequiredSCTs != nil {
+		apCopy.RequiredSCTs = append([]string(nil), tmpl.RequiredSCTs...)
+	}
+	if tmpl.IssuerNames != nil {
+		chain, err := issuerChainFromNames(tmpl.IssuerNames, options, *warnings)
+		if err != nil {
+			return nil, fmt.Errorf("cert profile '%s': %v", name, err)
+		}
+		apCopy.IssuersRaw = encodeIssuerChain(chain, warnings)
+	}
+	if tmpl.StorageModule != nil {
+		modName := tmpl.StorageModule.CaddyModule().ID.Name()
+		apCopy.StorageRaw = caddyconfig.JSONModuleObject(tmpl.StorageModule, "module", modName, warnings)
+	}
+	return &apCopy, nil
+}
+
+// applyCertProfile overlays the fields set by profile onto ap, leaving
+// alone anything profile doesn't specify (most importantly, ap's existing
+// issuer configuration, so that adopting a profile never silently discards
+// the site's acme_ca/email/acme_eab/acme_dns/acme_ari settings).
+func applyCertProfile(ap, profile *caddytls.AutomationPolicy) {
+	if profile.IssuersRaw != nil {
+		ap.Issuer = nil
+		ap.IssuersRaw = profile.IssuersRaw
+	}
+	if profile.KeyType != "" {
+		ap.KeyType = profile.KeyType
+	}
+	if profile.RenewalWindowRatio != 0 {
+		ap.RenewalWindowRatio = profile.RenewalWindowRatio
+	}
+	if profile.OnDemand {
+		ap.OnDemand = true
+	}
+	if profile.StorageRaw != nil {
+		ap.StorageRaw = profile.StorageRaw
+	}
+	if profile.MustStaple {
+		ap.MustStaple = true
+	}
+	if profile.DisableOCSPStapling {
+		ap.DisableOCSPStapling = true
+	}
+	if len(profile.RequiredSCTs) > 0 {
+		ap.RequiredSCTs = append(ap.RequiredSCTs, profile.RequiredSCTs...)
+	}
+}
+
+// acmeIssuerFromOptions builds a bare-bones ACMEIssuer from the "acme_ca",
+// "acme_ca_root", "acme_dns", "acme_eab", "acme_ari", and "email" global
+// options. It is shared by newBaseAutomationPolicy and issuerChainFromNames
+// so that an "acme" or "zerossl" issuer synthesized for a per-site fallback
+// chain is configured identically to one built from the global defaults.
+func acmeIssuerFromOptions(options map[string]interface{}, warnings []caddyconfig.Warning) (*caddytls.ACMEIssuer, error) {
+	acmeCA, _ := options["acme_ca"].(string)
+	acmeCARoot, hasACMECARoot := options["acme_ca_root"]
+	acmeDNS, hasACMEDNS := options["acme_dns"]
+	acmeEAB, hasACMEEAB := options["acme_eab"]
+	acmeARI, hasACMEARI := options["acme_ari"]
+	email, _ := options["email"].(string)
+
+	mgr := &caddytls.ACMEIssuer{
+		CA:    acmeCA,
+		Email: email,
+	}
+	if hasACMEARI {
+		mgr.RenewalInfo = acmeARI.(*caddytls.ACMEARIConfig)
+	}
+	if hasACMEDNS {
+		provName := acmeDNS.(string)
+		dnsProvModule, err := caddy.GetModule("dns.providers." + provName)
+		if err != nil {
+			return nil, fmt.Errorf("getting DNS provider module named '%s': %v", provName, err)
 		}
-		mgr := &caddytls.ACMEIssuer{
-			CA:    acmeCA.(string),
-			Email: email.(string),
+		mgr.Challenges = &caddytls.ChallengesConfig{
+			DNS: &caddytls.DNSChallengeConfig{
+				ProviderRaw: caddyconfig.JSONModuleObject(dnsProvModule.New(), "name", provName, &warnings),
+			},
 		}
-		if acmeDNS != nil {
-			provName := acmeDNS.(string)
-			dnsProvModule, err := caddy.GetModule("dns.providers." + provName)
+	}
+	if hasACMECARoot {
+		mgr.TrustedRootsPEMFiles = []string{acmeCARoot.(string)}
+	}
+	if hasACMEEAB {
+		mgr.ExternalAccount = acmeEAB.(*acme.EAB)
+	}
+	return mgr, nil
+}
+
+// issuerChainFromNames synthesizes an ordered fallback chain of issuers from
+// a list of issuer module names (e.g. "acme", "zerossl", "internal"), as used
+// by the Caddyfile's `tls { issuers ... }` subdirective. Each named issuer is
+// configured from the same global options newBaseAutomationPolicy draws from,
+// so that e.g. `issuers acme zerossl` produces the same ACME configuration
+// for both issuers, differing only in which CA they point to.
+func issuerChainFromNames(names []string, options map[string]interface{}, warnings []caddyconfig.Warning) ([]certmagic.Issuer, error) {
+	issuers := make([]certmagic.Issuer, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "internal":
+			issuers = append(issuers, new(caddytls.InternalIssuer))
+		case "acme", "zerossl":
+			mgr, err := acmeIssuerFromOptions(options, warnings)
 			if err != nil {
-				return nil, fmt.Errorf("getting DNS provider module named '%s': %v", provName, err)
+				return nil, err
 			}
-			mgr.Challenges = &caddytls.ChallengesConfig{
-				DNS: &caddytls.DNSChallengeConfig{
-					ProviderRaw: caddyconfig.JSONModuleObject(dnsProvModule.New(), "name", provName, &warnings),
-				},
+			if name == "zerossl" {
+				// the global acme_ca is meaningless to ZeroSSL, which has its own
+				// endpoint; clear it so this issuer doesn't end up pointed at
+				// whatever CA "acme" in the same chain uses, defeating the fallback
+				mgr.CA = ""
+				issuers = append(issuers, &caddytls.ZeroSSLIssuer{ACMEIssuer: mgr})
+			} else {
+				issuers = append(issuers, mgr)
 			}
+		default:
+			return nil, fmt.Errorf("unrecognized issuer in fallback chain: %s", name)
 		}
-		if acmeCARoot != nil {
-			mgr.TrustedRootsPEMFiles = []string{acmeCARoot.(string)}
-		}
-		if acmeEAB != nil {
-			mgr.ExternalAccount = acmeEAB.(*acme.EAB)
-		}
-		ap.Issuer = disambiguateACMEIssuer(mgr) // we'll encode it later
 	}
+	return issuers, nil
+}
 
-	return ap, nil
+// encodeIssuerChain JSON-encodes each issuer in chain as a module object,
+// suitable for use as an AutomationPolicy's IssuersRaw.
+func encodeIssuerChain(chain []certmagic.Issuer, warnings *[]caddyconfig.Warning) []json.RawMessage {
+	if len(chain) == 0 {
+		return nil
+	}
+	raw := make([]json.RawMessage, len(chain))
+	for i, issuer := range chain {
+		issuerName := issuer.(caddy.Module).CaddyModule().ID.Name()
+		raw[i] = caddyconfig.JSONModuleObject(issuer, "module", issuerName, warnings)
+	}
+	return raw
+}
+
+// issuersRawEqual returns true if a and b encode the same ordered chain of issuers.
+func issuersRawEqual(a, b []json.RawMessage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
 }
 
 // disambiguateACMEIssuer returns an issuer based on the properties of acmeIssuer.
@@ -463,9 +736,16 @@ func consolidateAutomationPolicies(aps []*caddytls.AutomationPolicy) []*caddytls
 			// otherwise the one without any subjects (a catch-all) would be
 			// eaten up by the one with subjects; and if both have subjects, we
 			// need to combine their lists
+			// RenewalInfo isn't compared here directly: it now lives on the
+			// issuer (ACMEIssuer.RenewalInfo) rather than the policy, so any
+			// difference in it is still caught via the IssuerRaw/IssuersRaw
+			// byte comparison, since it's part of the issuer's own JSON.
 			if bytes.Equal(aps[i].IssuerRaw, aps[j].IssuerRaw) &&
+				issuersRawEqual(aps[i].IssuersRaw, aps[j].IssuersRaw) &&
 				bytes.Equal(aps[i].StorageRaw, aps[j].StorageRaw) &&
 				aps[i].MustStaple == aps[j].MustStaple &&
+				aps[i].DisableOCSPStapling == aps[j].DisableOCSPStapling &&
+				reflect.DeepEqual(aps[i].RequiredSCTs, aps[j].RequiredSCTs) &&
 				aps[i].KeyType == aps[j].KeyType &&
 				aps[i].OnDemand == aps[j].OnDemand &&
 				aps[i].RenewalWindowRatio == aps[j].RenewalWindowRatio {
@@ -0,0 +1,188 @@
+// Copyright 2015 Matthew Holt and The Caddy Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpcaddyfile
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/caddyserver/caddy/v2/caddyconfig"
+	"github.com/caddyserver/caddy/v2/modules/caddytls"
+)
+
+func TestConsolidateAutomationPolicies(t *testing.T) {
+	for i, tc := range []struct {
+		input   []*caddytls.AutomationPolicy
+		wantLen int
+	}{
+		{
+			// identical policies, one with subjects and one catch-all, merge into one
+			input: []*caddytls.AutomationPolicy{
+				{Subjects: []string{"example.com"}},
+				{},
+			},
+			wantLen: 1,
+		},
+		{
+			// differing MustStaple keeps policies separate
+			input: []*caddytls.AutomationPolicy{
+				{Subjects: []string{"example.com"}, MustStaple: true},
+				{},
+			},
+			wantLen: 2,
+		},
+		{
+			// differing DisableOCSPStapling keeps policies separate
+			input: []*caddytls.AutomationPolicy{
+				{Subjects: []string{"example.com"}, DisableOCSPStapling: true},
+				{},
+			},
+			wantLen: 2,
+		},
+		{
+			// differing RequiredSCTs keeps policies separate
+			input: []*caddytls.AutomationPolicy{
+				{Subjects: []string{"example.com"}, RequiredSCTs: []string{"https://ct.example.com"}},
+				{},
+			},
+			wantLen: 2,
+		},
+		{
+			// identical RequiredSCTs merge into one
+			input: []*caddytls.AutomationPolicy{
+				{Subjects: []string{"example.com"}, RequiredSCTs: []string{"https://ct.example.com"}},
+				{RequiredSCTs: []string{"https://ct.example.com"}},
+			},
+			wantLen: 1,
+		},
+		{
+			// differing IssuersRaw keeps policies separate
+			input: []*caddytls.AutomationPolicy{
+				{Subjects: []string{"example.com"}, IssuersRaw: []json.RawMessage{[]byte(`{"module":"acme"}`)}},
+				{},
+			},
+			wantLen: 2,
+		},
+		{
+			// same IssuersRaw merge into one
+			input: []*caddytls.AutomationPolicy{
+				{Subjects: []string{"example.com"}, IssuersRaw: []json.RawMessage{[]byte(`{"module":"acme"}`)}},
+				{IssuersRaw: []json.RawMessage{[]byte(`{"module":"acme"}`)}},
+			},
+			wantLen: 1,
+		},
+		{
+			// RenewalInfo differences are ACME-issuer-specific and no longer live on
+			// the policy itself, but are still caught via the IssuerRaw bytes, since
+			// RenewalInfo serializes as part of the issuer's own JSON
+			input: []*caddytls.AutomationPolicy{
+				{Subjects: []string{"example.com"}, IssuerRaw: []byte(`{"module":"acme","renewal_info":{"enabled":true}}`)},
+				{IssuerRaw: []byte(`{"module":"acme"}`)},
+			},
+			wantLen: 2,
+		},
+	} {
+		actual := consolidateAutomationPolicies(tc.input)
+		if len(actual) != tc.wantLen {
+			t.Errorf("test %d: expected %d policies, got %d: %+v", i, tc.wantLen, len(actual), actual)
+		}
+	}
+}
+
+// TestApplyCertProfilePreservesAliasing is a regression test for a bug where
+// the tls.profile handling replaced a site's automation policy with the
+// profile template wholesale (ap = tmpl). When ap happened to be the
+// catch-all automation policy, also aliased elsewhere (e.g. already appended
+// to tlsApp.Automation.Policies), that repointing desynced the alias from the
+// policy actually being mutated. applyCertProfile fixes this by mutating ap
+// in place, so the fix should hold regardless of how many places alias ap.
+func TestApplyCertProfilePreservesAliasing(t *testing.T) {
+	ap := &caddytls.AutomationPolicy{Subjects: []string{"example.com"}}
+	policies := []*caddytls.AutomationPolicy{ap}
+	catchAllAP := ap
+
+	profile := &caddytls.AutomationPolicy{KeyType: "ed25519", OnDemand: true}
+	applyCertProfile(ap, profile)
+
+	if policies[0] != ap || catchAllAP != ap {
+		t.Fatalf("applyCertProfile must not repoint ap; aliases would desync")
+	}
+	if policies[0].KeyType != "ed25519" || !catchAllAP.OnDemand {
+		t.Errorf("expected profile fields visible through every alias, got %+v", policies[0])
+	}
+}
+
+// TestCertProfileBaseCopiesRequiredSCTs is a regression test for a bug where
+// certProfileBase returned a shallow copy of the named template, so its
+// RequiredSCTs slice still shared the template's backing array. A site block
+// appending to its own copy's RequiredSCTs (tls { ct_logs ... }) would then
+// silently clobber the template, and every other site block referencing the
+// same profile.
+func TestCertProfileBaseCopiesRequiredSCTs(t *testing.T) {
+	options := map[string]interface{}{
+		"cert_profiles": map[string]*caddytls.AutomationPolicy{
+			"strict": {RequiredSCTs: []string{"https://ct.example.com"}},
+		},
+	}
+	var warnings []caddyconfig.Warning
+
+	siteA, err := certProfileBase(options, &warnings, "strict")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	siteB, err := certProfileBase(options, &warnings, "strict")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if &siteA.RequiredSCTs[0] == &siteB.RequiredSCTs[0] {
+		t.Errorf("expected each site's RequiredSCTs to have its own backing array")
+	}
+
+	siteA.RequiredSCTs = append(siteA.RequiredSCTs, "https://ct2.example.com")
+
+	if len(siteB.RequiredSCTs) != 1 {
+		t.Errorf("expected siteB's RequiredSCTs to be unaffected by siteA's append, got %v", siteB.RequiredSCTs)
+	}
+	tmpl := options["cert_profiles"].(map[string]*caddytls.AutomationPolicy)["strict"]
+	if len(tmpl.RequiredSCTs) != 1 {
+		t.Errorf("expected the shared template's RequiredSCTs to be unaffected by siteA's append, got %v", tmpl.RequiredSCTs)
+	}
+}
+
+// TestAcmeIssuerFromIssuerNilIsAmbiguous is a regression test documenting why
+// the bind/tls.dns/tls.ari override loops in buildTLSApp must check
+// ap.IssuersRaw != nil before calling acmeIssuerFromIssuer: a nil ap.Issuer is
+// indistinguishable from "no issuer configured yet", so acmeIssuerFromIssuer
+// alone cannot detect that an explicit fallback chain (tls { issuers ... })
+// was already set, and would otherwise attach the override to a disconnected
+// issuer that's never part of that chain.
+func TestAcmeIssuerFromIssuerNilIsAmbiguous(t *testing.T) {
+	options := map[string]interface{}{}
+	chain, err := issuerChainFromNames([]string{"acme", "zerossl"}, options, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ap := &caddytls.AutomationPolicy{IssuersRaw: encodeIssuerChain(chain, &[]caddyconfig.Warning{})}
+
+	acmeIssuer, ok := acmeIssuerFromIssuer(ap.Issuer)
+	if !ok || acmeIssuer == nil {
+		t.Fatalf("acmeIssuerFromIssuer(nil) should report ok=true with a fresh issuer, since nil alone can't signal an explicit chain is in use")
+	}
+	if ap.IssuersRaw == nil {
+		t.Fatalf("test setup failed: expected ap.IssuersRaw to be set")
+	}
+	// the guard the bind/tls.dns/tls.ari loops rely on: seeing IssuersRaw
+	// already set is the only way to know not to use acmeIssuer above
+}
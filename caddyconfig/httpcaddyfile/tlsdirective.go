@@ -0,0 +1,151 @@
+// Copyright 2015 Matthew Holt and The Caddy Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpcaddyfile
+
+import (
+	"github.com/caddyserver/caddy/v2"
+	"github.com/caddyserver/caddy/v2/caddyconfig/caddyfile"
+	"github.com/caddyserver/caddy/v2/modules/caddytls"
+	"github.com/caddyserver/certmagic"
+)
+
+func init() {
+	RegisterDirective("tls", parseTLS)
+}
+
+// parseTLS parses the tls directive. Besides the on_demand subdirective, it
+// accepts the following (issuer and cert_loader are not parsed here):
+//
+//	tls {
+//	    issuers      <name>...
+//	    profile      <name>
+//	    ari {
+//	        off
+//	        check_interval <duration>
+//	        jitter         <duration>
+//	    }
+//	    must_staple
+//	    ocsp_stapling  off
+//	    ct_logs        <url>...
+//	    dns            <provider_name> {
+//	        <provider-specific config>
+//	    }
+//	}
+func parseTLS(h Helper) ([]ConfigValue, error) {
+	var configValues []ConfigValue
+
+	for h.Next() {
+		for h.NextBlock(0) {
+			switch h.Val() {
+			case "on_demand":
+				configValues = append(configValues, ConfigValue{Class: "tls.on_demand", Value: true})
+
+			case "issuers":
+				names := h.RemainingArgs()
+				if len(names) == 0 {
+					return nil, h.ArgErr()
+				}
+				configValues = append(configValues, ConfigValue{Class: "tls.cert_issuers", Value: names})
+
+			case "profile":
+				if !h.NextArg() {
+					return nil, h.ArgErr()
+				}
+				configValues = append(configValues, ConfigValue{Class: "tls.profile", Value: h.Val()})
+
+			case "ari":
+				ari, err := parseARIBlock(h.Dispenser)
+				if err != nil {
+					return nil, err
+				}
+				configValues = append(configValues, ConfigValue{Class: "tls.ari", Value: ari})
+
+			case "must_staple":
+				configValues = append(configValues, ConfigValue{Class: "tls.must_staple", Value: true})
+
+			case "ocsp_stapling":
+				disable := h.NextArg() && h.Val() == "off"
+				configValues = append(configValues, ConfigValue{Class: "tls.ocsp_stapling", Value: disable})
+
+			case "ct_logs":
+				urls := h.RemainingArgs()
+				if len(urls) == 0 {
+					return nil, h.ArgErr()
+				}
+				configValues = append(configValues, ConfigValue{Class: "tls.ct_logs", Value: urls})
+
+			case "dns":
+				if !h.NextArg() {
+					return nil, h.ArgErr()
+				}
+				provName := h.Val()
+				dnsProvModule, err := caddy.GetModule("dns.providers." + provName)
+				if err != nil {
+					return nil, h.Errf("getting DNS provider module named '%s': %v", provName, err)
+				}
+				provInst := dnsProvModule.New()
+				if unm, ok := provInst.(caddyfile.Unmarshaler); ok {
+					if err := unm.UnmarshalCaddyfile(h.NewFromNextSegment()); err != nil {
+						return nil, err
+					}
+				}
+				provider, ok := provInst.(certmagic.DNSProvider)
+				if !ok {
+					return nil, h.Errf("module '%s' is not a DNS provider", provName)
+				}
+				configValues = append(configValues, ConfigValue{Class: "tls.dns", Value: provider})
+
+				// issuer/cert_loader subdirectives are not parsed here
+			}
+		}
+	}
+
+	return configValues, nil
+}
+
+// parseARIBlock parses the body of a `tls { ari { ... } }` subdirective into
+// an ACMEARIConfig; ARI is enabled by default once the block is present,
+// and "off" turns it back off (e.g. to override a global acme_ari default
+// for one site).
+func parseARIBlock(d *caddyfile.Dispenser) (*caddytls.ACMEARIConfig, error) {
+	ari := &caddytls.ACMEARIConfig{Enabled: true}
+	for nesting := d.Nesting(); d.NextBlock(nesting); {
+		switch d.Val() {
+		case "off":
+			ari.Enabled = false
+		case "check_interval":
+			if !d.NextArg() {
+				return nil, d.ArgErr()
+			}
+			dur, err := caddy.ParseDuration(d.Val())
+			if err != nil {
+				return nil, d.Errf("parsing check_interval: %v", err)
+			}
+			ari.CheckInterval = caddy.Duration(dur)
+		case "jitter":
+			if !d.NextArg() {
+				return nil, d.ArgErr()
+			}
+			dur, err := caddy.ParseDuration(d.Val())
+			if err != nil {
+				return nil, d.Errf("parsing jitter: %v", err)
+			}
+			ari.Jitter = caddy.Duration(dur)
+		default:
+			return nil, d.Errf("unrecognized ari subdirective: %s", d.Val())
+		}
+	}
+	return ari, nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2015 Matthew Holt and The Caddy Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpcaddyfile
+
+import (
+	"strconv"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/caddyserver/caddy/v2/caddyconfig/caddyfile"
+	"github.com/caddyserver/caddy/v2/modules/caddytls"
+)
+
+func init() {
+	RegisterGlobalOption("cert_profiles", parseOptCertProfiles)
+	RegisterGlobalOption("acme_ari", parseOptACMEARI)
+}
+
+// parseOptCertProfiles parses the global `cert_profiles` option, which
+// defines named automation policy templates that site blocks can apply
+// as their base policy via `tls { profile <name> }`. A profile only needs
+// to specify what it wants to override; anything it leaves unset (most
+// notably the issuer) is inherited from the site's own automation policy
+// rather than being blanked out, so adopting a profile never silently
+// drops a site's acme_ca/email/acme_eab/acme_dns/acme_ari configuration.
+//
+//	cert_profiles <name> {
+//	    issuers        <name>...
+//	    key_type       <type>
+//	    renewal_window_ratio <ratio>
+//	    on_demand
+//	    storage <module> {
+//	        <module-specific config>
+//	    }
+//	    must_staple
+//	    ocsp_stapling  off
+//	    ct_logs        <url>...
+//	}
+func parseOptCertProfiles(d *caddyfile.Dispenser, existingVal any) (any, error) {
+	profiles, ok := existingVal.(map[string]*caddytls.AutomationPolicy)
+	if !ok {
+		profiles = make(map[string]*caddytls.AutomationPolicy)
+	}
+
+	for d.Next() {
+		if !d.NextArg() {
+			return nil, d.ArgErr()
+		}
+		name := d.Val()
+		if _, ok := profiles[name]; ok {
+			return nil, d.Errf("cert profile named '%s' defined more than once", name)
+		}
+
+		ap := new(caddytls.AutomationPolicy)
+		for nesting := d.Nesting(); d.NextBlock(nesting); {
+			switch d.Val() {
+			case "issuers":
+				names := d.RemainingArgs()
+				if len(names) == 0 {
+					return nil, d.ArgErr()
+				}
+				// these name a fallback chain exactly like the tls directive's
+				// own "issuers" subdirective, but can't be resolved to concrete
+				// issuer modules yet: doing so needs the acme_ca/acme_dns/etc.
+				// global options, which may not all have been parsed yet at this
+				// point in the Caddyfile. certProfileBase resolves these once
+				// the full set of global options is available.
+				ap.IssuerNames = names
+			case "key_type":
+				if !d.NextArg() {
+					return nil, d.ArgErr()
+				}
+				ap.KeyType = d.Val()
+			case "renewal_window_ratio":
+				if !d.NextArg() {
+					return nil, d.ArgErr()
+				}
+				ratio, err := strconv.ParseFloat(d.Val(), 64)
+				if err != nil {
+					return nil, d.Errf("parsing renewal_window_ratio: %v", err)
+				}
+				ap.RenewalWindowRatio = ratio
+			case "on_demand":
+				ap.OnDemand = true
+			case "storage":
+				if !d.NextArg() {
+					return nil, d.ArgErr()
+				}
+				modName := d.Val()
+				storageModule, err := caddy.GetModule("caddy.storage." + modName)
+				if err != nil {
+					return nil, d.Errf("getting storage module named '%s': %v", modName, err)
+				}
+				storageInst := storageModule.New()
+				if unm, ok := storageInst.(caddyfile.Unmarshaler); ok {
+					if err := unm.UnmarshalCaddyfile(d.NewFromNextSegment()); err != nil {
+						return nil, err
+					}
+				}
+				// deferred to StorageModule rather than encoded to StorageRaw
+				// here, since JSON-encoding it to collect warnings needs the
+				// final warnings slice, which isn't available until buildTLSApp
+				ap.StorageModule = storageInst
+			case "must_staple":
+				ap.MustStaple = true
+			case "ocsp_stapling":
+				ap.DisableOCSPStapling = d.NextArg() && d.Val() == "off"
+			case "ct_logs":
+				urls := d.RemainingArgs()
+				if len(urls) == 0 {
+					return nil, d.ArgErr()
+				}
+				ap.RequiredSCTs = append(ap.RequiredSCTs, urls...)
+			default:
+				return nil, d.Errf("unrecognized cert_profiles subdirective: %s", d.Val())
+			}
+		}
+
+		profiles[name] = ap
+	}
+
+	return profiles, nil
+}
+
+// parseOptACMEARI parses the global `acme_ari` option, which enables and
+// configures ACME Renewal Information (RFC 9773) as the default for all
+// ACME-issued certificates, unless overridden per-site with `tls { ari }`.
+//
+//	acme_ari {
+//	    off
+//	    check_interval <duration>
+//	    jitter         <duration>
+//	}
+func parseOptACMEARI(d *caddyfile.Dispenser, existingVal any) (any, error) {
+	if !d.Next() {
+		return nil, d.ArgErr()
+	}
+	ari, err := parseARIBlock(d)
+	if err != nil {
+		return nil, err
+	}
+	return ari, nil
+}
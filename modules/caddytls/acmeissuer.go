@@ -0,0 +1,132 @@
+// Copyright 2015 Matthew Holt and The Caddy Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package caddytls
+
+import (
+	"encoding/json"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/mholt/acmez/acme"
+)
+
+// ACMEIssuer manages certificates using the ACME protocol.
+type ACMEIssuer struct {
+	// The CA's endpoint URL, e.g. "https://acme-v02.api.letsencrypt.org/directory".
+	CA string `json:"ca,omitempty"`
+
+	// Your email address, so the CA can contact you if necessary.
+	Email string `json:"email,omitempty"`
+
+	// An optional list of PEM files of CA certificates to trust, if
+	// the CA's endpoint is not already trusted by the system/OS trust store.
+	TrustedRootsPEMFiles []string `json:"trusted_roots_pem_files,omitempty"`
+
+	// The External Account Binding to associate with this issuer.
+	ExternalAccount *acme.EAB `json:"external_account,omitempty"`
+
+	// Configures the various ACME challenges.
+	Challenges *ChallengesConfig `json:"challenges,omitempty"`
+
+	// Enables and configures ACME Renewal Information (RFC 9773) for this
+	// issuer's certificates, superseding RenewalWindowRatio when the CA
+	// supports it.
+	RenewalInfo *ACMEARIConfig `json:"renewal_info,omitempty"`
+}
+
+// CaddyModule returns the Caddy module information.
+func (*ACMEIssuer) CaddyModule() caddy.ModuleInfo {
+	return caddy.ModuleInfo{
+		ID:  "tls.issuance.acme",
+		New: func() caddy.Module { return new(ACMEIssuer) },
+	}
+}
+
+// GetACMEIssuer returns iss, since it is itself the canonical ACME issuer.
+// This makes ACMEIssuer satisfy interfaces that need to unwrap a possibly
+// ACME-wrapping issuer to get at the underlying ACME configuration.
+func (iss *ACMEIssuer) GetACMEIssuer() *ACMEIssuer { return iss }
+
+// ZeroSSLIssuer wraps an ACMEIssuer in order to support ZeroSSL's API for
+// automatically generating EAB credentials, since ZeroSSL's ACME endpoint
+// otherwise requires EAB that most users won't have handy.
+type ZeroSSLIssuer struct {
+	*ACMEIssuer
+}
+
+// CaddyModule returns the Caddy module information.
+func (*ZeroSSLIssuer) CaddyModule() caddy.ModuleInfo {
+	return caddy.ModuleInfo{
+		ID:  "tls.issuance.zerossl",
+		New: func() caddy.Module { return new(ZeroSSLIssuer) },
+	}
+}
+
+// GetACMEIssuer returns the underlying ACMEIssuer.
+func (iss *ZeroSSLIssuer) GetACMEIssuer() *ACMEIssuer { return iss.ACMEIssuer }
+
+// InternalIssuer issues certificates using Caddy's internal CA,
+// intended for internal/private subjects that don't qualify for a
+// publicly-trusted certificate.
+type InternalIssuer struct{}
+
+// CaddyModule returns the Caddy module information.
+func (*InternalIssuer) CaddyModule() caddy.ModuleInfo {
+	return caddy.ModuleInfo{
+		ID:  "tls.issuance.internal",
+		New: func() caddy.Module { return new(InternalIssuer) },
+	}
+}
+
+// ChallengesConfig configures the ACME challenges used to prove
+// domain/subject ownership.
+type ChallengesConfig struct {
+	// The host to bind to when opening listeners for HTTP/TLS-ALPN challenges.
+	BindHost string `json:"bind_host,omitempty"`
+
+	// Configures the DNS challenge.
+	DNS *DNSChallengeConfig `json:"dns,omitempty"`
+}
+
+// DNSChallengeConfig configures the ACME DNS challenge.
+type DNSChallengeConfig struct {
+	// The DNS provider module that will create and remove
+	// the temporary TXT records needed to complete the challenge.
+	ProviderRaw json.RawMessage `json:"provider,omitempty" caddy:"namespace=dns.providers inline_key=name"`
+}
+
+// ACMEARIConfig configures ACME Renewal Information (RFC 9773). When
+// enabled, the issuer queries the CA's renewalInfo endpoint to learn its
+// suggested renewal window instead of relying solely on RenewalWindowRatio.
+type ACMEARIConfig struct {
+	// Whether to query the CA's renewalInfo endpoint at all. If the CA
+	// doesn't support ARI, or a query fails, RenewalWindowRatio is used
+	// as usual.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// How often to poll the CA's renewalInfo endpoint for an updated
+	// suggested window.
+	CheckInterval caddy.Duration `json:"check_interval,omitempty"`
+
+	// A random amount of time, up to this duration, added to the chosen
+	// renewal time within the suggested window, so that many instances
+	// relying on the same CA don't all renew at once.
+	Jitter caddy.Duration `json:"jitter,omitempty"`
+}
+
+func init() {
+	caddy.RegisterModule(new(ACMEIssuer))
+	caddy.RegisterModule(new(ZeroSSLIssuer))
+	caddy.RegisterModule(new(InternalIssuer))
+}
@@ -0,0 +1,137 @@
+// Copyright 2015 Matthew Holt and The Caddy Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package caddytls
+
+import (
+	"encoding/json"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/caddyserver/certmagic"
+)
+
+// TLS represents a process-wide TLS configuration.
+type TLS struct {
+	CertificatesRaw caddy.ModuleMap   `json:"certificates,omitempty" caddy:"namespace=tls.certificates"`
+	Automation      *AutomationConfig `json:"automation,omitempty"`
+}
+
+// CaddyModule returns the Caddy module information.
+func (*TLS) CaddyModule() caddy.ModuleInfo {
+	return caddy.ModuleInfo{
+		ID:  "tls",
+		New: func() caddy.Module { return new(TLS) },
+	}
+}
+
+func init() {
+	caddy.RegisterModule(new(TLS))
+}
+
+// AutomationConfig governs the automated management of TLS certificates.
+type AutomationConfig struct {
+	// The list of automation policies. The first policy matching
+	// a given certificate/subject is the one used for that certificate.
+	Policies []*AutomationPolicy `json:"policies,omitempty"`
+
+	// On-demand TLS defaults, used for certificates obtained at handshake-time.
+	OnDemand *OnDemandConfig `json:"on_demand,omitempty"`
+}
+
+// AutomationPolicy designates the policy for automating the management
+// (obtaining, renewing) of managed TLS certificates for one or more subjects.
+type AutomationPolicy struct {
+	// Which subjects this policy applies to.
+	Subjects []string `json:"subjects,omitempty"`
+
+	// The issuer to use for this policy. Only one of IssuerRaw or
+	// IssuersRaw should be set; IssuersRaw takes precedence.
+	IssuerRaw json.RawMessage `json:"issuer,omitempty" caddy:"namespace=tls.issuance inline_key=module"`
+
+	// An ordered list of issuers to try in turn; if an earlier issuer
+	// fails to obtain a certificate, the next one is tried, and so on.
+	IssuersRaw []json.RawMessage `json:"issuers,omitempty" caddy:"namespace=tls.issuance inline_key=module"`
+
+	// The type of key to generate for certificates covered by this policy.
+	KeyType string `json:"key_type,omitempty"`
+
+	// Whether to obtain certificates for subjects covered by this policy
+	// on-demand (at handshake-time) rather than eagerly.
+	OnDemand bool `json:"on_demand,omitempty"`
+
+	// How long before a certificate's expiration to try renewing it, as
+	// a ratio of its total lifetime.
+	RenewalWindowRatio float64 `json:"renewal_window_ratio,omitempty"`
+
+	// The storage module to use for this policy's certificates and keys.
+	StorageRaw json.RawMessage `json:"storage,omitempty" caddy:"namespace=caddy.storage inline_key=module"`
+
+	// Whether to require OCSP stapling to be set up for this policy's
+	// certificates before they are considered complete.
+	MustStaple bool `json:"must_staple,omitempty"`
+
+	// If true, OCSP stapling is disabled for certificates covered by this
+	// policy, even if the issuer supports it.
+	DisableOCSPStapling bool `json:"disable_ocsp_stapling,omitempty"`
+
+	// A list of Certificate Transparency log URLs that issued certificates
+	// must have SCTs from, in addition to whatever the issuer itself embeds.
+	RequiredSCTs []string `json:"required_scts,omitempty"`
+
+	// Issuer is the decoded, concrete issuer this policy will use to obtain
+	// certificates. Caddyfile adapters populate this field directly (instead
+	// of IssuerRaw) while synthesizing a policy, then encode it into
+	// IssuerRaw once the policy is otherwise complete.
+	Issuer certmagic.Issuer `json:"-"`
+
+	// IssuerNames holds an unresolved fallback chain of issuer module names
+	// (e.g. from a cert_profiles template's "issuers" subdirective), set
+	// when the concrete issuers can't be built yet because doing so depends
+	// on global options that may not all be available at parse time. It is
+	// resolved to IssuersRaw once the full set of global options is known.
+	IssuerNames []string `json:"-"`
+
+	// StorageModule is the decoded, concrete storage module this policy will
+	// use, analogous to Issuer; set instead of StorageRaw while synthesizing
+	// a policy (e.g. from a cert_profiles template's "storage" subdirective),
+	// then encoded into StorageRaw once warnings can be collected.
+	StorageModule caddy.Module `json:"-"`
+}
+
+// OnDemandConfig configures on-demand TLS, for obtaining
+// needed certificates at handshake-time rather than ahead of time.
+type OnDemandConfig struct {
+	// If Caddy needs to obtain/renew a certificate during a TLS handshake,
+	// it will perform a quick HTTP request to this ask URL, which should be
+	// a private endpoint exclusively for this purpose, to determine if it
+	// should be allowed to try to get a certificate for the given name.
+	Ask string `json:"ask,omitempty"`
+}
+
+// CertificateLoader is a type that can load certificates. Certificates
+// can optionally be associated with tags.
+type CertificateLoader interface {
+	LoadCertificates() ([]Certificate, error)
+}
+
+// Certificate is a TLS certificate that can be loaded and managed.
+type Certificate struct {
+	Certificate []byte
+	Tags        []string
+}
+
+// AutomateLoader is a list of subject names for which certificates should
+// be obtained and managed automatically, as if there were an automation
+// policy for each of them.
+type AutomateLoader []string